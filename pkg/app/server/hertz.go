@@ -19,9 +19,10 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"os"
 	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/cloudwego/hertz/pkg/app/middlewares/server/recovery"
@@ -33,15 +34,80 @@ import (
 // Hertz is the core struct of hertz.
 type Hertz struct {
 	*route.Engine
+
+	// extraListeners holds the fds left over after WithSocketActivation
+	// claims the first one as Engine's own listener. Engine.Serve runs the
+	// same router and middleware registered on Engine for each of these
+	// too, so a process handed several fds serves all of them
+	// identically instead of some of them 404ing behind a second, empty
+	// *route.Engine.
+	extraListeners []net.Listener
+
+	signalHandler SignalHandler
+	runHooks      []func(*Hertz) error
+	shutdownHooks []func(context.Context) error
 }
 
 // New creates a hertz instance without any default config.
+//
+// The listener is bound synchronously, here, rather than left for Run to
+// discover lazily: that way a bind failure surfaces immediately to New's
+// caller instead of turning up later via Spin's errCh, after OnRun hooks
+// and the systemd READY=1 notification have already fired on the
+// assumption that the listener was up.
 func New(opts ...config.Option) *Hertz {
 	options := config.NewOptions(opts)
-	h := &Hertz{
-		Engine: route.NewEngine(options),
+
+	var extraListeners []net.Listener
+	if options.SocketActivation {
+		var err error
+		extraListeners, err = adoptSocketActivationListeners(options)
+		if err != nil {
+			hlog.Fatalf("HERTZ: socket activation failed: %v", err)
+		}
+	} else if err := ensureListener(options); err != nil {
+		hlog.Fatalf("HERTZ: failed to bind listener: %v", err)
 	}
-	return h
+
+	return &Hertz{
+		Engine:         route.NewEngine(options),
+		extraListeners: extraListeners,
+		signalHandler:  newDefaultSignalHandler(),
+	}
+}
+
+// ensureListener synchronously binds options.Listener if WithSocketActivation
+// hasn't already supplied one, so a bad address is reported to New's caller
+// right away instead of surfacing asynchronously once Run starts.
+func ensureListener(options *config.Options) error {
+	if options.Listener != nil {
+		return nil
+	}
+	ln, err := net.Listen(options.Network, options.Addr)
+	if err != nil {
+		return err
+	}
+	options.Listener = ln
+	return nil
+}
+
+// adoptSocketActivationListeners finds the listener(s) systemd passed to
+// this process via socket activation and wires the first one into options
+// so Engine binds to it instead of creating its own. Any remaining fds are
+// returned for the caller to serve on the same Engine via Engine.Serve,
+// alongside the one Engine already owns.
+func adoptSocketActivationListeners(options *config.Options) ([]net.Listener, error) {
+	listeners, err := socketActivationListeners()
+	if err != nil {
+		return nil, err
+	}
+	if len(listeners) == 0 {
+		hlog.Warnf("HERTZ: WithSocketActivation set but systemd passed no listen fds, falling back to normal listening")
+		return nil, ensureListener(options)
+	}
+
+	options.Listener = listeners[0]
+	return listeners[1:], nil
 }
 
 // Default creates a hertz instance with default middlewares.
@@ -52,50 +118,124 @@ func Default(opts ...config.Option) *Hertz {
 	return h
 }
 
-// Spin runs the server until catching os.Signal.
-// SIGTERM triggers immediately close.
-// SIGHUP|SIGINT triggers graceful shutdown.
+// Spin runs the server until its SignalHandler decides to shut down (by
+// default: SIGTERM force-closes, SIGINT/SIGHUP gracefully shut down).
+//
+// Engine's own listener and every extraListeners entry are already bound by
+// the time Spin is called (New binds them synchronously), so it's safe to
+// run OnRun hooks and send the systemd READY=1 notification before Run's
+// accept loop has even started.
 func (h *Hertz) Spin() {
-	errCh := make(chan error)
+	errCh := make(chan error, 1+len(h.extraListeners))
 	go func() {
 		errCh <- h.Run()
 	}()
+	for _, ln := range h.extraListeners {
+		ln := ln
+		go func() {
+			errCh <- h.Engine.Serve(ln)
+		}()
+	}
+
+	h.runRunHooks()
+
+	watchdogDone := make(chan struct{})
+	go h.notifySystemd(watchdogDone)
+
+	err := h.waitSignal(errCh)
+	close(watchdogDone)
 
-	if err := waitSignal(errCh); err != nil {
+	if err != nil {
 		hlog.Errorf("HERTZ: Receive close signal: error=%v", err)
-		if err := h.Engine.Close(); err != nil {
-			hlog.Errorf("HERTZ: Close error=%v", err)
-		}
+		h.closeAll()
 		return
 	}
 
+	if err := sdNotify("STOPPING=1"); err != nil {
+		hlog.Warnf("HERTZ: sd_notify STOPPING failed: error=%v", err)
+	}
+
 	hlog.Infof("HERTZ: Begin graceful shutdown, wait at most num=%d seconds...", h.GetOptions().ExitWaitTimeout/time.Second)
 
 	ctx, cancel := context.WithTimeout(context.Background(), h.GetOptions().ExitWaitTimeout)
 	defer cancel()
 
+	h.runShutdownHooks(ctx)
+	h.shutdownAll(ctx)
+}
+
+// notifySystemd tells systemd (via NOTIFY_SOCKET) that the server is ready,
+// then pings the watchdog at half of WATCHDOG_USEC until done is closed.
+// Both are no-ops when the corresponding env vars aren't set, which is the
+// common case when Hertz isn't running under a Type=notify unit.
+func (h *Hertz) notifySystemd(done <-chan struct{}) {
+	if err := sdNotify(fmt.Sprintf("READY=1\nMAINPID=%d", os.Getpid())); err != nil {
+		hlog.Warnf("HERTZ: sd_notify READY failed: error=%v", err)
+	}
+
+	interval, ok := watchdogInterval()
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := sdNotify("WATCHDOG=1"); err != nil {
+				hlog.Warnf("HERTZ: sd_notify WATCHDOG failed: error=%v", err)
+			}
+		}
+	}
+}
+
+// closeAll force-closes Engine, which tears down its own listener along
+// with every extraListeners connection Engine.Serve accepted for it.
+func (h *Hertz) closeAll() {
+	if err := h.Engine.Close(); err != nil {
+		hlog.Errorf("HERTZ: Close error=%v", err)
+	}
+}
+
+// shutdownAll gracefully shuts down Engine, which covers its own listener
+// and every extraListeners entry Engine.Serve is running, waiting at most
+// until ctx is done.
+func (h *Hertz) shutdownAll(ctx context.Context) {
 	if err := h.Shutdown(ctx); err != nil {
 		hlog.Errorf("HERTZ: Shutdown error=%v", err)
 	}
 }
 
-func waitSignal(errCh chan error) error {
+// waitSignal blocks until h.signalHandler picks ActionShutdown or
+// ActionForceClose, or errCh fires because a listener died on its own.
+// ActionContinue and ActionReload loop back to waiting; ActionReload also
+// tells systemd a reload is in progress.
+func (h *Hertz) waitSignal(errCh chan error) error {
 	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM)
-
-	select {
-	case sig := <-signals:
-		switch sig {
-		case syscall.SIGTERM:
-			// force exit
-			return errors.New(sig.String()) // nolint
-		case syscall.SIGHUP, syscall.SIGINT:
-			// graceful shutdown
-			return nil
+	signal.Notify(signals, h.signalHandler.Signals()...)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case sig := <-signals:
+			switch h.signalHandler.Handle(h, sig) {
+			case ActionForceClose:
+				return errors.New(sig.String()) // nolint
+			case ActionShutdown:
+				return nil
+			case ActionReload:
+				if err := sdNotify("RELOADING=1"); err != nil {
+					hlog.Warnf("HERTZ: sd_notify RELOADING failed: error=%v", err)
+				}
+			case ActionContinue:
+				// no-op: the handler's func(*Hertz) already ran, e.g. to
+				// rotate logs; keep waiting for the next signal.
+			}
+		case err := <-errCh:
+			return err
 		}
-	case err := <-errCh:
-		return err
 	}
-
-	return nil
 }
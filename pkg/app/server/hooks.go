@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"os"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+)
+
+// SetSignalHandler replaces Spin's SignalHandler. Use this to run under
+// platforms the default handler doesn't cover, or to change what any of
+// Spin's signals do; OnSignal is usually simpler if you only need to add
+// or override a handful of signals.
+func (h *Hertz) SetSignalHandler(sh SignalHandler) {
+	h.signalHandler = sh
+}
+
+// OnSignal registers fn to be called when sig is received, replacing
+// whatever Spin would otherwise do for that signal (including the
+// defaults for SIGINT/SIGHUP/SIGTERM). It only affects the default
+// SignalHandler; call SetSignalHandler first if you need something else
+// entirely.
+func (h *Hertz) OnSignal(sig os.Signal, fn func(*Hertz) Action) {
+	dh, ok := h.signalHandler.(*mapSignalHandler)
+	if !ok {
+		hlog.Warnf("HERTZ: OnSignal has no effect once SetSignalHandler has installed a custom SignalHandler")
+		return
+	}
+	dh.on(sig, fn)
+}
+
+// OnShutdown registers fn to run, in registration order, once Spin begins
+// a graceful shutdown. Every fn receives the same context.Context passed
+// to Engine.Shutdown, which is canceled after ExitWaitTimeout.
+func (h *Hertz) OnShutdown(fn func(context.Context) error) {
+	h.shutdownHooks = append(h.shutdownHooks, fn)
+}
+
+// OnRun registers fn to run, in registration order, once the server's
+// listener(s) are up and before Spin starts waiting for a signal. An error
+// from fn is logged; it doesn't stop the server or the remaining hooks.
+func (h *Hertz) OnRun(fn func(*Hertz) error) {
+	h.runHooks = append(h.runHooks, fn)
+}
+
+func (h *Hertz) runRunHooks() {
+	for _, fn := range h.runHooks {
+		if err := fn(h); err != nil {
+			hlog.Errorf("HERTZ: OnRun hook error=%v", err)
+		}
+	}
+}
+
+func (h *Hertz) runShutdownHooks(ctx context.Context) {
+	for _, fn := range h.shutdownHooks {
+		if err := fn(ctx); err != nil {
+			hlog.Errorf("HERTZ: OnShutdown hook error=%v", err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestHertzOnRunHooksRunInOrder(t *testing.T) {
+	h := &Hertz{}
+	var calls []int
+	h.OnRun(func(*Hertz) error { calls = append(calls, 1); return nil })
+	h.OnRun(func(*Hertz) error { calls = append(calls, 2); return nil })
+
+	h.runRunHooks()
+
+	if !reflect.DeepEqual(calls, []int{1, 2}) {
+		t.Fatalf("Unexpected call order: %v. Expecting [1 2]", calls)
+	}
+}
+
+func TestHertzOnShutdownHooksRunInOrder(t *testing.T) {
+	h := &Hertz{}
+	var calls []int
+	h.OnShutdown(func(context.Context) error { calls = append(calls, 1); return nil })
+	h.OnShutdown(func(context.Context) error { calls = append(calls, 2); return nil })
+
+	h.runShutdownHooks(context.Background())
+
+	if !reflect.DeepEqual(calls, []int{1, 2}) {
+		t.Fatalf("Unexpected call order: %v. Expecting [1 2]", calls)
+	}
+}
+
+func TestHertzOnSignalOverridesDefaultHandler(t *testing.T) {
+	h := &Hertz{signalHandler: newDefaultSignalHandler()}
+
+	h.OnSignal(syscall.SIGINT, func(*Hertz) Action { return ActionContinue })
+
+	if action := h.signalHandler.Handle(h, syscall.SIGINT); action != ActionContinue {
+		t.Fatalf("Unexpected action after OnSignal: %v. Expecting ActionContinue", action)
+	}
+}
+
+type stubSignalHandler struct{}
+
+func (stubSignalHandler) Signals() []os.Signal            { return nil }
+func (stubSignalHandler) Handle(*Hertz, os.Signal) Action { return ActionShutdown }
+
+func TestHertzOnSignalHasNoEffectAfterSetSignalHandler(t *testing.T) {
+	custom := stubSignalHandler{}
+	h := &Hertz{signalHandler: custom}
+
+	h.OnSignal(syscall.SIGINT, func(*Hertz) Action { return ActionContinue })
+
+	if h.signalHandler != SignalHandler(custom) {
+		t.Fatalf("OnSignal should not replace a custom SignalHandler installed via SetSignalHandler")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import "github.com/cloudwego/hertz/pkg/common/config"
+
+// WithSocketActivation makes New/Default skip creating their own listener
+// and instead adopt the file descriptor(s) passed in by systemd socket
+// activation (LISTEN_FDS/LISTEN_PID/LISTEN_FDNAMES). It has no effect if
+// the process wasn't started with socket activation.
+//
+// See https://www.freedesktop.org/software/systemd/man/sd_listen_fds.html.
+func WithSocketActivation() config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.SocketActivation = true
+	}}
+}
+
+// WithEnableTrailer makes the HTTP/1.1 request reader merge chunked-transfer
+// trailer fields into the request header once the terminating chunk has
+// been read, for any trailer name the client declared via a preceding
+// `Trailer:` header, by passing options.EnableTrailer through to
+// utils.ReadChunkedBody. It has no effect on requests that aren't
+// chunked-encoded.
+func WithEnableTrailer() config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.EnableTrailer = true
+	}}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"os"
+	"syscall"
+)
+
+// Action tells Spin how to react to a signal a SignalHandler was asked
+// about.
+type Action int
+
+const (
+	// ActionContinue leaves Spin waiting for the next signal; the current
+	// one had no effect on the server's lifecycle.
+	ActionContinue Action = iota
+	// ActionShutdown begins Spin's graceful shutdown, bounded by
+	// ExitWaitTimeout.
+	ActionShutdown
+	// ActionForceClose closes all listeners and connections immediately,
+	// skipping the graceful drain.
+	ActionForceClose
+	// ActionReload notifies systemd (RELOADING=1, if NOTIFY_SOCKET is set)
+	// and leaves Spin waiting for the next signal; it's up to the caller's
+	// handler to actually reload whatever needs reloading.
+	ActionReload
+)
+
+// SignalHandler decides which signals Hertz.Spin listens for and what to
+// do with each one it receives. Hertz.SetSignalHandler installs a custom
+// implementation; the default preserves Spin's historical behavior
+// (SIGTERM force-closes, SIGINT/SIGHUP gracefully shut down).
+type SignalHandler interface {
+	// Signals returns the signals Spin should register with os/signal.
+	Signals() []os.Signal
+	// Handle is called with a signal returned by Signals once Spin
+	// receives it, and picks the Action to take in response.
+	Handle(h *Hertz, sig os.Signal) Action
+}
+
+// mapSignalHandler is the default SignalHandler. It dispatches each
+// registered signal to its own func(*Hertz) Action and is what backs
+// Hertz.OnSignal.
+type mapSignalHandler struct {
+	signals  []os.Signal
+	handlers map[os.Signal]func(*Hertz) Action
+}
+
+func newDefaultSignalHandler() *mapSignalHandler {
+	m := &mapSignalHandler{handlers: make(map[os.Signal]func(*Hertz) Action)}
+	for _, sig := range defaultShutdownSignals {
+		m.on(sig, func(*Hertz) Action { return ActionShutdown })
+	}
+	m.on(syscall.SIGTERM, func(*Hertz) Action { return ActionForceClose })
+	return m
+}
+
+func (m *mapSignalHandler) Signals() []os.Signal {
+	return m.signals
+}
+
+func (m *mapSignalHandler) Handle(h *Hertz, sig os.Signal) Action {
+	if fn, ok := m.handlers[sig]; ok {
+		return fn(h)
+	}
+	return ActionContinue
+}
+
+func (m *mapSignalHandler) on(sig os.Signal, fn func(*Hertz) Action) {
+	if _, exists := m.handlers[sig]; !exists {
+		m.signals = append(m.signals, sig)
+	}
+	m.handlers[sig] = fn
+}
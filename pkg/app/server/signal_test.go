@@ -0,0 +1,75 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestMapSignalHandlerDefaultDispatch(t *testing.T) {
+	m := newDefaultSignalHandler()
+
+	if action := m.Handle(nil, syscall.SIGTERM); action != ActionForceClose {
+		t.Fatalf("Unexpected action for SIGTERM: %v. Expecting ActionForceClose", action)
+	}
+	if action := m.Handle(nil, syscall.SIGINT); action != ActionShutdown {
+		t.Fatalf("Unexpected action for SIGINT: %v. Expecting ActionShutdown", action)
+	}
+	if action := m.Handle(nil, syscall.SIGUSR1); action != ActionContinue {
+		t.Fatalf("Unexpected action for an unregistered signal: %v. Expecting ActionContinue", action)
+	}
+}
+
+func TestMapSignalHandlerOnOverridesDefault(t *testing.T) {
+	m := newDefaultSignalHandler()
+	before := len(m.Signals())
+
+	m.on(syscall.SIGINT, func(*Hertz) Action { return ActionReload })
+
+	if action := m.Handle(nil, syscall.SIGINT); action != ActionReload {
+		t.Fatalf("Unexpected action after override: %v. Expecting ActionReload", action)
+	}
+	if got := len(m.Signals()); got != before {
+		t.Fatalf("Overriding an existing signal shouldn't register it twice: got %d signals, want %d", got, before)
+	}
+}
+
+func TestMapSignalHandlerOnRegistersNewSignal(t *testing.T) {
+	m := newDefaultSignalHandler()
+	before := len(m.Signals())
+
+	m.on(syscall.SIGUSR2, func(*Hertz) Action { return ActionContinue })
+
+	if got := len(m.Signals()); got != before+1 {
+		t.Fatalf("Unexpected signal count after registering a new one: got %d, want %d", got, before+1)
+	}
+
+	var found bool
+	for _, sig := range m.Signals() {
+		if sig == os.Signal(syscall.SIGUSR2) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Signals() doesn't include the newly registered signal")
+	}
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultShutdownSignals are the signals the default SignalHandler treats
+// as ActionShutdown. SIGHUP is included here and nowhere else because it
+// doesn't exist on Windows.
+var defaultShutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGHUP}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listenFDsStart is the first file descriptor systemd hands over during
+// socket activation; see sd_listen_fds(3).
+const listenFDsStart = 3
+
+// listenFD is one file descriptor systemd passed to this process via
+// socket activation, along with the name it was given (if any) via
+// LISTEN_FDNAMES.
+type listenFD struct {
+	fd   int
+	name string
+}
+
+// parseListenFDs parses the sd_listen_fds(3) env vars, returning the fds
+// systemd passed to this process, or nil if the process wasn't launched
+// with socket activation, or the env vars don't target pid. getenv and pid
+// are parameterized so this can be unit tested without touching the real
+// environment or process.
+func parseListenFDs(getenv func(string) string, pid int) ([]listenFD, error) {
+	pidStr := getenv("LISTEN_PID")
+	fdsStr := getenv("LISTEN_FDS")
+	if pidStr == "" || fdsStr == "" {
+		return nil, nil
+	}
+
+	envPID, err := strconv.Atoi(pidStr)
+	if err != nil {
+		return nil, fmt.Errorf("systemd: invalid LISTEN_PID %q: %w", pidStr, err)
+	}
+	if envPID != pid {
+		// The activation env vars were inherited from a parent that didn't
+		// clear them before exec'ing us; they don't target this process.
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(fdsStr)
+	if err != nil {
+		return nil, fmt.Errorf("systemd: invalid LISTEN_FDS %q: %w", fdsStr, err)
+	}
+
+	var names []string
+	if fdNames := getenv("LISTEN_FDNAMES"); fdNames != "" {
+		names = strings.Split(fdNames, ":")
+	}
+
+	fds := make([]listenFD, 0, n)
+	for i := 0; i < n; i++ {
+		fd := listenFDsStart + i
+		name := fmt.Sprintf("LISTEN_FD_%d", fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		fds = append(fds, listenFD{fd: fd, name: name})
+	}
+
+	return fds, nil
+}
+
+// socketActivationListeners returns the listeners passed in by systemd
+// socket activation, or nil if the process wasn't launched with socket
+// activation, or the activation env vars don't target this process.
+//
+// See https://www.freedesktop.org/software/systemd/man/sd_listen_fds.html.
+func socketActivationListeners() ([]net.Listener, error) {
+	fds, err := parseListenFDs(os.Getenv, os.Getpid())
+	if err != nil || len(fds) == 0 {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(fds))
+	for _, lfd := range fds {
+		// net.FileListener dups the fd, so it's safe to close our copy once
+		// it returns; this also works for both TCP and Unix socket fds.
+		f := os.NewFile(uintptr(lfd.fd), lfd.name)
+		ln, err := net.FileListener(f)
+		f.Close() //nolint:errcheck
+		if err != nil {
+			return nil, fmt.Errorf("systemd: fd %d (%s): %w", lfd.fd, lfd.name, err)
+		}
+		listeners = append(listeners, ln)
+	}
+
+	return listeners, nil
+}
+
+// notifySocketAddr turns the value of NOTIFY_SOCKET into a dial-able
+// unixgram address, translating systemd's "@" convention for a Linux
+// abstract namespace socket into the leading NUL net.Dial expects.
+func notifySocketAddr(raw string) string {
+	if raw != "" && raw[0] == '@' {
+		return "\x00" + raw[1:]
+	}
+	return raw
+}
+
+// sdNotify sends state to the socket named by NOTIFY_SOCKET, the protocol
+// systemd units with Type=notify use to report readiness and liveness. It's
+// a no-op when NOTIFY_SOCKET isn't set, so it's always safe to call.
+func sdNotify(state string) error {
+	addr := notifySocketAddr(os.Getenv("NOTIFY_SOCKET"))
+	if addr == "" {
+		return nil
+	}
+
+	conn, err := net.Dial("unixgram", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// watchdogInterval reports how often WATCHDOG=1 should be sent, derived as
+// half of WATCHDOG_USEC per sd_watchdog_enabled(3), and whether the
+// watchdog is enabled for this process at all.
+func watchdogInterval() (time.Duration, bool) {
+	usecStr := os.Getenv("WATCHDOG_USEC")
+	if usecStr == "" {
+		return 0, false
+	}
+
+	if pidStr := os.Getenv("WATCHDOG_PID"); pidStr != "" {
+		if pid, err := strconv.Atoi(pidStr); err == nil && pid != os.Getpid() {
+			return 0, false
+		}
+	}
+
+	usec, err := strconv.ParseInt(usecStr, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func fakeGetenv(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestSystemdParseListenFDsNotActivated(t *testing.T) {
+	fds, err := parseListenFDs(fakeGetenv(nil), 123)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if fds != nil {
+		t.Fatalf("Unexpected fds: %v. Expecting nil", fds)
+	}
+}
+
+func TestSystemdParseListenFDsWrongPid(t *testing.T) {
+	fds, err := parseListenFDs(fakeGetenv(map[string]string{
+		"LISTEN_PID": "999",
+		"LISTEN_FDS": "1",
+	}), 123)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if fds != nil {
+		t.Fatalf("Unexpected fds: %v. Expecting nil", fds)
+	}
+}
+
+func TestSystemdParseListenFDsInvalidPid(t *testing.T) {
+	_, err := parseListenFDs(fakeGetenv(map[string]string{
+		"LISTEN_PID": "not-a-number",
+		"LISTEN_FDS": "1",
+	}), 123)
+	if err == nil {
+		t.Fatalf("Expecting an error, got nil")
+	}
+}
+
+func TestSystemdParseListenFDsInvalidCount(t *testing.T) {
+	_, err := parseListenFDs(fakeGetenv(map[string]string{
+		"LISTEN_PID": "123",
+		"LISTEN_FDS": "not-a-number",
+	}), 123)
+	if err == nil {
+		t.Fatalf("Expecting an error, got nil")
+	}
+}
+
+func TestSystemdParseListenFDsDefaultNames(t *testing.T) {
+	fds, err := parseListenFDs(fakeGetenv(map[string]string{
+		"LISTEN_PID": "123",
+		"LISTEN_FDS": "2",
+	}), 123)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []listenFD{
+		{fd: 3, name: "LISTEN_FD_3"},
+		{fd: 4, name: "LISTEN_FD_4"},
+	}
+	if !reflect.DeepEqual(fds, expected) {
+		t.Fatalf("Unexpected fds: %v. Expecting %v", fds, expected)
+	}
+}
+
+func TestSystemdParseListenFDsWithNames(t *testing.T) {
+	fds, err := parseListenFDs(fakeGetenv(map[string]string{
+		"LISTEN_PID":     "123",
+		"LISTEN_FDS":     "2",
+		"LISTEN_FDNAMES": "http:",
+	}), 123)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []listenFD{
+		{fd: 3, name: "http"},
+		{fd: 4, name: "LISTEN_FD_4"},
+	}
+	if !reflect.DeepEqual(fds, expected) {
+		t.Fatalf("Unexpected fds: %v. Expecting %v", fds, expected)
+	}
+}
+
+func TestSystemdNotifySocketAddr(t *testing.T) {
+	cases := map[string]string{
+		"":                 "",
+		"/run/notify.sock": "/run/notify.sock",
+		"@hertz":           "\x00hertz",
+	}
+	for raw, expected := range cases {
+		if got := notifySocketAddr(raw); got != expected {
+			t.Fatalf("notifySocketAddr(%q) = %q. Expecting %q", raw, got, expected)
+		}
+	}
+}
+
+func TestSdNotifyNoOpWithoutNotifySocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := sdNotify("READY=1"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func TestSdNotifySendsDatagram(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "notify.sock")
+	pc, err := net.ListenPacket("unixgram", sockPath)
+	if err != nil {
+		t.Fatalf("Unexpected error listening: %s", err)
+	}
+	defer pc.Close()
+
+	t.Setenv("NOTIFY_SOCKET", sockPath)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- sdNotify("READY=1") }()
+
+	buf := make([]byte, 64)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error reading: %s", err)
+	}
+	if got := string(buf[:n]); got != "READY=1" {
+		t.Fatalf("Unexpected datagram: %q. Expecting %q", got, "READY=1")
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Unexpected error from sdNotify: %s", err)
+	}
+}
+
+func TestWatchdogIntervalDisabled(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	if _, ok := watchdogInterval(); ok {
+		t.Fatalf("Expecting watchdog to be disabled without WATCHDOG_USEC")
+	}
+}
+
+func TestWatchdogIntervalHalvesUsec(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "2000000")
+	t.Setenv("WATCHDOG_PID", "")
+
+	interval, ok := watchdogInterval()
+	if !ok {
+		t.Fatalf("Expecting watchdog to be enabled")
+	}
+	if interval != time.Second {
+		t.Fatalf("Unexpected interval: %s. Expecting %s", interval, time.Second)
+	}
+}
+
+func TestWatchdogIntervalWrongPid(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "2000000")
+	t.Setenv("WATCHDOG_PID", "1")
+
+	if os.Getpid() == 1 {
+		t.Skip("can't use an unreachable pid in this environment")
+	}
+	if _, ok := watchdogInterval(); ok {
+		t.Fatalf("Expecting watchdog to be disabled for a pid that isn't us")
+	}
+}
+
+func TestWatchdogIntervalInvalidUsec(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "not-a-number")
+	if _, ok := watchdogInterval(); ok {
+		t.Fatalf("Expecting watchdog to be disabled for an invalid WATCHDOG_USEC")
+	}
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import "github.com/cloudwego/hertz/pkg/common/config"
+
+// WithEnableTrailer makes the HTTP/1.1 response reader merge
+// chunked-transfer trailer fields into the response header once the
+// terminating chunk has been read, for any trailer name the server
+// declared via a preceding `Trailer:` header, by passing
+// options.EnableTrailer through to utils.ReadChunkedBody. It has no effect
+// on responses that aren't chunked-encoded.
+func WithEnableTrailer() config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.EnableTrailer = true
+	}}
+}
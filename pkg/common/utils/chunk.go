@@ -0,0 +1,321 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"net/textproto"
+
+	errs "github.com/cloudwego/hertz/pkg/common/errors"
+	"github.com/cloudwego/hertz/pkg/network"
+)
+
+var (
+	errBrokenChunk   = errs.NewPublic("cannot find crlf at the end of chunk")
+	errBrokenTrailer = errs.NewPublic("error when reading chunked trailer")
+)
+
+// ChunkHeader is the result of parsing a single `chunk-size [ ";" chunk-ext ] CRLF`
+// line, as described by RFC 7230 Section 4.1.1.
+type ChunkHeader struct {
+	// Size is the decoded chunk-size.
+	Size int
+	// Extensions holds the chunk-ext name/value pairs, if any were present
+	// on the chunk-size line. A valueless extension is recorded with an
+	// empty string value. Nil when the chunk carried no extensions.
+	Extensions map[string]string
+}
+
+// ParseChunkSize parses the chunk-size from r and discards any chunk
+// extensions that may follow it on the same line.
+func ParseChunkSize(r network.Reader) (int, error) {
+	header, err := ParseChunkHeader(r)
+	if err != nil {
+		return -1, err
+	}
+	return header.Size, nil
+}
+
+// ParseChunkHeader parses a chunk-size line, exposing any chunk extensions
+// found alongside the size. Callers that don't care about extensions can
+// keep using ParseChunkSize.
+func ParseChunkHeader(r network.Reader) (ChunkHeader, error) {
+	n, err := readHexInt(r)
+	if err != nil {
+		return ChunkHeader{Size: -1}, err
+	}
+
+	header := ChunkHeader{Size: n}
+
+	ext, err := readChunkExt(r)
+	if err != nil {
+		return ChunkHeader{Size: -1}, err
+	}
+	if trimmed := bytes.TrimSpace(ext); len(trimmed) > 0 {
+		if exts := parseChunkExtensions(trimmed); len(exts) > 0 {
+			header.Extensions = exts
+		}
+	}
+
+	if err := SkipCRLF(r); err != nil {
+		return ChunkHeader{Size: -1}, err
+	}
+
+	return header, nil
+}
+
+// readChunkExt consumes and returns everything between the chunk-size (or a
+// preceding chunk-ext) and the terminating CRLF, without consuming the CRLF
+// itself.
+func readChunkExt(r network.Reader) ([]byte, error) {
+	var ext []byte
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return nil, errBrokenChunk
+		}
+		if c[0] == '\r' {
+			return ext, nil
+		}
+		ext = append(ext, c[0])
+		if err := r.Skip(1); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseChunkExtensions splits a raw `;name=value;name2=value2` chunk-ext
+// string into a name/value map. Malformed pairs are skipped rather than
+// failing the parse, in keeping with the tolerant whitespace handling the
+// rest of this parser already does.
+func parseChunkExtensions(raw []byte) map[string]string {
+	ext := make(map[string]string)
+	for _, part := range bytes.Split(raw, []byte(";")) {
+		part = bytes.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+		kv := bytes.SplitN(part, []byte("="), 2)
+		name := string(bytes.TrimSpace(kv[0]))
+		if name == "" {
+			continue
+		}
+		value := ""
+		if len(kv) == 2 {
+			value = string(bytes.Trim(bytes.TrimSpace(kv[1]), `"`))
+		}
+		ext[name] = value
+	}
+	return ext
+}
+
+// ReadChunkedBody reads a complete chunked-transfer message body from r,
+// appending each chunk's data to dst, until it reaches the terminating
+// zero-size chunk.
+//
+// When enableTrailer is true (the EnableTrailer option on route.Engine or
+// client.Client was set), trailer field lines that follow the terminating
+// chunk are merged into trailer via ParseTrailer, honoring the declared
+// `Trailer:` names. When it's false, the trailer section is still consumed
+// — so the connection is positioned at the next message — but discarded.
+func ReadChunkedBody(r network.Reader, dst *bytes.Buffer, trailer TrailerSetter, enableTrailer bool) error {
+	for {
+		header, err := ParseChunkHeader(r)
+		if err != nil {
+			return err
+		}
+		if header.Size == 0 {
+			if enableTrailer {
+				return ParseTrailer(r, trailer)
+			}
+			return discardTrailer(r)
+		}
+
+		data, err := r.Peek(header.Size)
+		if err != nil {
+			return errBrokenChunk
+		}
+		dst.Write(data)
+
+		if err := r.Skip(header.Size); err != nil {
+			return err
+		}
+		if err := SkipCRLF(r); err != nil {
+			return err
+		}
+	}
+}
+
+// discardTrailer consumes the trailer field lines that follow the
+// terminating chunk without merging them anywhere, for callers that didn't
+// opt into EnableTrailer.
+func discardTrailer(r network.Reader) error {
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return errBrokenTrailer
+		}
+		if c[0] == '\r' {
+			return SkipCRLF(r)
+		}
+		if _, _, err := readTrailerLine(r); err != nil {
+			return err
+		}
+	}
+}
+
+// TrailerSetter is implemented by *protocol.Trailer, the type
+// RequestHeader and ResponseHeader each hold to track their declared and
+// merged trailer fields. It lets ParseTrailer merge trailer fields into
+// whichever header's trailer the caller is assembling without this package
+// importing protocol (and creating an import cycle).
+type TrailerSetter interface {
+	// Set adds or replaces the value for a trailer field.
+	Set(key, value string)
+	// Trailer returns the set of field names the caller advertised via a
+	// preceding `Trailer:` header. Only these names are accepted.
+	Trailer() map[string]string
+}
+
+// framingTrailerFields lists the trailer names RFC 7230 Section 4.1.2 calls
+// out as unsafe to merge in from a trailer, since a sender could use them
+// to change how the message was framed, routed, or evaluated after the
+// recipient already parsed it under the original headers. ParseTrailer
+// ignores these even if the sender declared them via Trailer:, the same
+// stance net/http's transfer codec takes for Transfer-Encoding,
+// Content-Length and Trailer itself.
+var framingTrailerFields = map[string]bool{
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Trailer":           true,
+	"Host":              true,
+}
+
+// ParseTrailer reads trailer field lines from r, as sent after the
+// terminating zero-size chunk, and merges them into h. It stops at the
+// blank line that ends the trailer section.
+//
+// Only field names previously declared via h.Trailer() are accepted, and
+// framing-sensitive names are never merged in even if declared; undeclared
+// fields are otherwise ignored, matching RFC 7230 Section 4.1.2's guidance
+// that recipients MAY ignore trailer fields the sender didn't announce.
+func ParseTrailer(r network.Reader, h TrailerSetter) error {
+	declared := h.Trailer()
+
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return errBrokenTrailer
+		}
+		if c[0] == '\r' {
+			return SkipCRLF(r)
+		}
+
+		key, value, err := readTrailerLine(r)
+		if err != nil {
+			return err
+		}
+		if framingTrailerFields[textproto.CanonicalMIMEHeaderKey(key)] {
+			continue
+		}
+		if _, ok := declared[key]; ok {
+			h.Set(key, value)
+		}
+	}
+}
+
+// readTrailerLine reads a single `name: value CRLF` trailer line.
+func readTrailerLine(r network.Reader) (key, value string, err error) {
+	var line []byte
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return "", "", errBrokenTrailer
+		}
+		if c[0] == '\n' {
+			if err := r.Skip(1); err != nil {
+				return "", "", err
+			}
+			break
+		}
+		line = append(line, c[0])
+		if err := r.Skip(1); err != nil {
+			return "", "", err
+		}
+	}
+
+	line = bytes.TrimRight(line, "\r")
+	n := bytes.IndexByte(line, ':')
+	if n < 0 {
+		return "", "", errBrokenTrailer
+	}
+
+	return string(bytes.TrimSpace(line[:n])), string(bytes.TrimSpace(line[n+1:])), nil
+}
+
+// readHexInt reads a hexadecimal chunk-size, stopping at the first
+// non-hex-digit byte (whitespace, ';' or '\r').
+func readHexInt(r network.Reader) (int, error) {
+	n := 0
+	seenHexDigit := false
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return -1, errBrokenChunk
+		}
+
+		v := hexToInt(c[0])
+		if v < 0 {
+			if !seenHexDigit {
+				return -1, errBrokenChunk
+			}
+			return n, nil
+		}
+
+		if err := r.Skip(1); err != nil {
+			return -1, err
+		}
+		seenHexDigit = true
+		n = n*16 + v
+	}
+}
+
+func hexToInt(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	default:
+		return -1
+	}
+}
+
+// SkipCRLF consumes a "\r\n" sequence from r, returning an error if it is
+// not present.
+func SkipCRLF(r network.Reader) error {
+	c, err := r.Peek(2)
+	if err != nil {
+		return errBrokenChunk
+	}
+	if c[0] != '\r' || c[1] != '\n' {
+		return errBrokenChunk
+	}
+	return r.Skip(2)
+}
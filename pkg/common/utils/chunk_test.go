@@ -17,6 +17,8 @@
 package utils
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/cloudwego/hertz/pkg/common/test/mock"
@@ -85,3 +87,128 @@ func TestChunkReadFalseCRLF(t *testing.T) {
 		t.Fatalf("Expecting error, but get nil")
 	}
 }
+
+func TestChunkParseChunkHeaderWithExtension(t *testing.T) {
+	chunkSizeBody := "a;foo=bar;baz\r\n"
+	zr := mock.NewZeroCopyReader(chunkSizeBody)
+	header, err := ParseChunkHeader(zr)
+	if err != nil {
+		t.Fatalf("Unexpected error for ParseChunkHeader: %s", err)
+	}
+	if header.Size != 10 {
+		t.Fatalf("Unexpected chunk size: %d. Expecting 10", header.Size)
+	}
+	expected := map[string]string{"foo": "bar", "baz": ""}
+	if !reflect.DeepEqual(header.Extensions, expected) {
+		t.Fatalf("Unexpected extensions: %v. Expecting %v", header.Extensions, expected)
+	}
+}
+
+func TestChunkParseChunkHeaderWithoutExtension(t *testing.T) {
+	chunkSizeBody := "a\r\n"
+	zr := mock.NewZeroCopyReader(chunkSizeBody)
+	header, err := ParseChunkHeader(zr)
+	if err != nil {
+		t.Fatalf("Unexpected error for ParseChunkHeader: %s", err)
+	}
+	if header.Size != 10 {
+		t.Fatalf("Unexpected chunk size: %d. Expecting 10", header.Size)
+	}
+	if header.Extensions != nil {
+		t.Fatalf("Unexpected extensions: %v. Expecting nil", header.Extensions)
+	}
+}
+
+func TestChunkParseChunkHeaderWhitespaceOnlyIsNotAnExtension(t *testing.T) {
+	// whitespace with no ';' must not be mistaken for an (empty) extension
+	// list; Extensions should stay nil, the whitespace being the same
+	// padding TestChunkParseChunkSizeCorrectWhiteSpace accepts after the size.
+	chunkSizeBody := "0   \r\n"
+	zr := mock.NewZeroCopyReader(chunkSizeBody)
+	header, err := ParseChunkHeader(zr)
+	if err != nil {
+		t.Fatalf("Unexpected error for ParseChunkHeader: %s", err)
+	}
+	if header.Extensions != nil {
+		t.Fatalf("Unexpected extensions: %v. Expecting nil", header.Extensions)
+	}
+}
+
+type testTrailerSetter struct {
+	declared map[string]string
+	fields   map[string]string
+}
+
+func (t *testTrailerSetter) Set(key, value string) {
+	if t.fields == nil {
+		t.fields = make(map[string]string)
+	}
+	t.fields[key] = value
+}
+
+func (t *testTrailerSetter) Trailer() map[string]string {
+	return t.declared
+}
+
+func TestChunkParseTrailerMergesDeclaredFields(t *testing.T) {
+	trailerBody := "Foo: bar\r\nUndeclared: nope\r\n\r\n"
+	zr := mock.NewZeroCopyReader(trailerBody)
+	h := &testTrailerSetter{declared: map[string]string{"Foo": ""}}
+
+	if err := ParseTrailer(zr, h); err != nil {
+		t.Fatalf("Unexpected error for ParseTrailer: %s", err)
+	}
+	expected := map[string]string{"Foo": "bar"}
+	if !reflect.DeepEqual(h.fields, expected) {
+		t.Fatalf("Unexpected trailer fields: %v. Expecting %v", h.fields, expected)
+	}
+}
+
+func TestChunkParseTrailerIgnoresFramingFieldsEvenIfDeclared(t *testing.T) {
+	trailerBody := "Content-Length: 0\r\ncontent-length: 0\r\nFoo: bar\r\n\r\n"
+	zr := mock.NewZeroCopyReader(trailerBody)
+	h := &testTrailerSetter{declared: map[string]string{"Content-Length": "", "Foo": ""}}
+
+	if err := ParseTrailer(zr, h); err != nil {
+		t.Fatalf("Unexpected error for ParseTrailer: %s", err)
+	}
+	expected := map[string]string{"Foo": "bar"}
+	if !reflect.DeepEqual(h.fields, expected) {
+		t.Fatalf("Unexpected trailer fields: %v. Expecting %v", h.fields, expected)
+	}
+}
+
+func TestChunkReadChunkedBodyMergesTrailerWhenEnabled(t *testing.T) {
+	body := "5\r\nhello\r\n0\r\nFoo: bar\r\n\r\n"
+	zr := mock.NewZeroCopyReader(body)
+	h := &testTrailerSetter{declared: map[string]string{"Foo": ""}}
+
+	var dst bytes.Buffer
+	if err := ReadChunkedBody(zr, &dst, h, true); err != nil {
+		t.Fatalf("Unexpected error for ReadChunkedBody: %s", err)
+	}
+	if dst.String() != "hello" {
+		t.Fatalf("Unexpected body: %q. Expecting %q", dst.String(), "hello")
+	}
+	expected := map[string]string{"Foo": "bar"}
+	if !reflect.DeepEqual(h.fields, expected) {
+		t.Fatalf("Unexpected trailer fields: %v. Expecting %v", h.fields, expected)
+	}
+}
+
+func TestChunkReadChunkedBodyDiscardsTrailerWhenDisabled(t *testing.T) {
+	body := "5\r\nhello\r\n0\r\nFoo: bar\r\n\r\n"
+	zr := mock.NewZeroCopyReader(body)
+	h := &testTrailerSetter{declared: map[string]string{"Foo": ""}}
+
+	var dst bytes.Buffer
+	if err := ReadChunkedBody(zr, &dst, h, false); err != nil {
+		t.Fatalf("Unexpected error for ReadChunkedBody: %s", err)
+	}
+	if dst.String() != "hello" {
+		t.Fatalf("Unexpected body: %q. Expecting %q", dst.String(), "hello")
+	}
+	if len(h.fields) != 0 {
+		t.Fatalf("Unexpected trailer fields: %v. Expecting none merged", h.fields)
+	}
+}
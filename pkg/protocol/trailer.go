@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import "strings"
+
+// Trailer holds the chunked-transfer trailer fields declared via a
+// `Trailer:` header and, once read, the values that were actually merged
+// in.
+//
+// RequestHeader and ResponseHeader each hold one as an unexported field and
+// expose it through a Trailer() accessor, the same way they expose other
+// header state; callers pass that accessor's return value straight to
+// utils.ReadChunkedBody/utils.ParseTrailer, which only need the
+// utils.TrailerSetter methods below. Trailer isn't embedded anonymously in
+// either header, so its Set/Get don't collide with the header's own
+// Set/Get for regular (non-trailer) fields.
+type Trailer struct {
+	declared map[string]string
+	fields   map[string]string
+}
+
+// Set adds or replaces the value for a trailer field.
+func (t *Trailer) Set(key, value string) {
+	if t.fields == nil {
+		t.fields = make(map[string]string)
+	}
+	t.fields[key] = value
+}
+
+// Get returns the value merged in for a trailer field, if any.
+func (t *Trailer) Get(key string) string {
+	return t.fields[key]
+}
+
+// Trailer returns the set of field names declared via a preceding
+// `Trailer:` header. Only these names may be merged in by ParseTrailer.
+func (t *Trailer) Trailer() map[string]string {
+	return t.declared
+}
+
+// SetTrailer records the field names advertised by a `Trailer:` header
+// value, discarding whatever was declared before.
+func (t *Trailer) SetTrailer(value string) {
+	t.declared = nil
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if t.declared == nil {
+			t.declared = make(map[string]string)
+		}
+		t.declared[name] = ""
+	}
+}
+
+// ResetTrailer clears both the declared trailer names and any merged
+// values, for header reuse between requests/responses.
+func (t *Trailer) ResetTrailer() {
+	t.declared = nil
+	t.fields = nil
+}